@@ -0,0 +1,268 @@
+// Command g11n-extract scans Go source for g11n message structs and writes
+// a locale skeleton containing their default messages.
+//
+// It walks the packages given as arguments, finds struct fields tagged
+// `default:"..."` (the same convention g11n.MessageFactory.Init reads), and
+// merges the resulting `TypeName.FieldName` keys into an existing locale
+// file, preserving translations that are already in place and marking
+// entries that no longer exist in source as obsolete.
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"go/ast"
+	"log"
+	"os"
+	"reflect"
+	"sort"
+	"strings"
+
+	g11nLocale "github.com/sgatev/g11n/locale"
+
+	"golang.org/x/tools/go/packages"
+	"gopkg.in/yaml.v3"
+)
+
+// obsoletePrefix marks a key in the output locale file whose source message
+// has been removed, so translators can tell it apart from live messages.
+const obsoletePrefix = "_obsolete_"
+
+// defaultSuffix stores, alongside a translated key, the default pattern
+// that was extracted for it last time the tool ran. It lets the next run
+// tell a genuinely new translation apart from one whose default pattern
+// simply changed in source.
+const defaultSuffix = "#default"
+
+// message is a single extracted `TypeName.FieldName` entry.
+type message struct {
+	key     string
+	pattern string
+}
+
+func main() {
+	format := flag.String("format", "json", "locale file format (json, yaml)")
+	out := flag.String("out", "", "locale file to write (required)")
+	in := flag.String("in", "", "existing locale file to merge into (optional)")
+	flag.Parse()
+
+	if *out == "" || flag.NArg() == 0 {
+		fmt.Fprintln(os.Stderr, "usage: g11n-extract -out locale.json [-in locale.json] [-format json] <packages...>")
+		os.Exit(2)
+	}
+
+	messages, err := extractMessages(flag.Args())
+	if err != nil {
+		log.Fatalf("g11n-extract: %v", err)
+	}
+
+	existing := map[string]string{}
+	if *in != "" {
+		existing, err = loadLocale(*in, *format)
+		if err != nil {
+			log.Fatalf("g11n-extract: %v", err)
+		}
+	}
+
+	merged, report := mergeMessages(messages, existing)
+	reportChanges(report)
+
+	if err := writeLocale(*out, *format, merged); err != nil {
+		log.Fatalf("g11n-extract: %v", err)
+	}
+}
+
+// extractMessages walks patterns with go/packages and collects every
+// `default`-tagged struct field it finds.
+func extractMessages(patterns []string) ([]message, error) {
+	cfg := &packages.Config{
+		Mode: packages.NeedName | packages.NeedFiles | packages.NeedSyntax | packages.NeedTypes,
+	}
+
+	pkgs, err := packages.Load(cfg, patterns...)
+	if err != nil {
+		return nil, fmt.Errorf("loading packages: %w", err)
+	}
+
+	var messages []message
+	for _, pkg := range pkgs {
+		for _, file := range pkg.Syntax {
+			messages = append(messages, extractFileMessages(file)...)
+		}
+	}
+
+	return messages, nil
+}
+
+// extractFileMessages collects the default-tagged struct fields declared in
+// a single file.
+func extractFileMessages(file *ast.File) []message {
+	var messages []message
+
+	ast.Inspect(file, func(n ast.Node) bool {
+		typeSpec, ok := n.(*ast.TypeSpec)
+		if !ok {
+			return true
+		}
+
+		structType, ok := typeSpec.Type.(*ast.StructType)
+		if !ok {
+			return true
+		}
+
+		for _, field := range structType.Fields.List {
+			if field.Tag == nil {
+				continue
+			}
+
+			tag := reflect.StructTag(strings.Trim(field.Tag.Value, "`"))
+			pattern, ok := tag.Lookup("default")
+			if !ok {
+				continue
+			}
+
+			for _, name := range field.Names {
+				messages = append(messages, message{
+					key:     fmt.Sprintf("%v.%v", typeSpec.Name.Name, name.Name),
+					pattern: pattern,
+				})
+			}
+		}
+
+		return true
+	})
+
+	return messages
+}
+
+// changeReport summarizes how extracted messages differ from an existing
+// locale file.
+type changeReport struct {
+	added   []string
+	changed []string
+	removed []string
+}
+
+// isMetaKey reports whether key is bookkeeping this tool adds to the locale
+// file (an obsolete marker or a stored default pattern) rather than a real
+// message key extracted from source.
+func isMetaKey(key string) bool {
+	return strings.HasPrefix(key, obsoletePrefix) || strings.HasSuffix(key, defaultSuffix)
+}
+
+// mergeMessages merges freshly extracted messages into an existing
+// translated dictionary. Keys that are already translated keep their
+// translation; if the default pattern extracted for them changed since the
+// last run, they are also reported as changed so a translator can review
+// them. Keys no longer present in source are kept but marked obsolete.
+func mergeMessages(messages []message, existing map[string]string) (map[string]string, changeReport) {
+	merged := map[string]string{}
+	seen := map[string]bool{}
+
+	var report changeReport
+	for _, msg := range messages {
+		seen[msg.key] = true
+		merged[msg.key+defaultSuffix] = msg.pattern
+
+		translation, hasTranslation := existing[msg.key]
+		if !hasTranslation {
+			merged[msg.key] = msg.pattern
+			report.added = append(report.added, msg.key)
+			continue
+		}
+
+		merged[msg.key] = translation
+
+		if previousDefault, ok := existing[msg.key+defaultSuffix]; ok && previousDefault != msg.pattern {
+			report.changed = append(report.changed, msg.key)
+		}
+	}
+
+	for key, translation := range existing {
+		if strings.HasPrefix(key, obsoletePrefix) {
+			// Keep entries marked obsolete by an earlier run, unless their
+			// message is back in source.
+			if !seen[strings.TrimPrefix(key, obsoletePrefix)] {
+				merged[key] = translation
+			}
+			continue
+		}
+
+		if seen[key] || isMetaKey(key) {
+			continue
+		}
+
+		merged[obsoletePrefix+key] = translation
+		report.removed = append(report.removed, key)
+	}
+
+	sort.Strings(report.added)
+	sort.Strings(report.changed)
+	sort.Strings(report.removed)
+
+	return merged, report
+}
+
+// reportChanges prints a summary of new, changed and removed messages to
+// stderr.
+func reportChanges(report changeReport) {
+	for _, key := range report.added {
+		fmt.Fprintf(os.Stderr, "new: %v\n", key)
+	}
+	for _, key := range report.changed {
+		fmt.Fprintf(os.Stderr, "changed: %v\n", key)
+	}
+	for _, key := range report.removed {
+		fmt.Fprintf(os.Stderr, "removed: %v\n", key)
+	}
+}
+
+// loadLocale reads an existing locale file through the same loader registry
+// MessageFactory.LoadLocale uses at runtime, so extraction always merges
+// against the format the app will actually load.
+func loadLocale(path, format string) (map[string]string, error) {
+	if _, err := os.Stat(path); os.IsNotExist(err) {
+		return map[string]string{}, nil
+	}
+
+	loader, ok := g11nLocale.GetLoader(format)
+	if !ok {
+		return nil, fmt.Errorf("unknown locale format %q", format)
+	}
+
+	return loader.Load(path), nil
+}
+
+// localeWriter encodes a flat locale dictionary to the bytes of a locale
+// file in a particular format.
+type localeWriter func(dictionary map[string]string) ([]byte, error)
+
+// localeWriters mirrors g11nLocale's loader registry for the direction that
+// registry doesn't cover: serializing a dictionary back out to a locale
+// file. It ships writers for the two formats g11nLocale supports out of the
+// box; a project registering a custom g11nLocale loader should add a
+// matching entry here too.
+var localeWriters = map[string]localeWriter{
+	"json": func(dictionary map[string]string) ([]byte, error) {
+		return json.MarshalIndent(dictionary, "", "  ")
+	},
+	"yaml": func(dictionary map[string]string) ([]byte, error) {
+		return yaml.Marshal(dictionary)
+	},
+}
+
+// writeLocale writes dictionary to path in the given format.
+func writeLocale(path, format string, dictionary map[string]string) error {
+	writer, ok := localeWriters[format]
+	if !ok {
+		return fmt.Errorf("unknown locale format %q", format)
+	}
+
+	data, err := writer(dictionary)
+	if err != nil {
+		return fmt.Errorf("encoding %v: %w", path, err)
+	}
+
+	return os.WriteFile(path, data, 0644)
+}
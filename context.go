@@ -0,0 +1,141 @@
+package g11n
+
+import (
+	"context"
+	"net/http"
+
+	"golang.org/x/text/language"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+)
+
+// tagContextKey is the unexported key under which a negotiated locale tag is
+// stashed in a context.Context.
+type tagContextKey struct{}
+
+// WithTag returns a copy of ctx carrying tag as the negotiated locale for a
+// subsequent call to MessageFactory.WithContext.
+func WithTag(ctx context.Context, tag language.Tag) context.Context {
+	return context.WithValue(ctx, tagContextKey{}, tag)
+}
+
+// TagFromContext returns the locale tag stashed in ctx by WithTag, and
+// whether one was found.
+func TagFromContext(ctx context.Context) (language.Tag, bool) {
+	tag, ok := ctx.Value(tagContextKey{}).(language.Tag)
+	return tag, ok
+}
+
+// Messages is a locale-scoped, read-only view over a MessageFactory, bound
+// to a single request's negotiated language.Tag. Unlike MessageFactory.Init,
+// which mutates factory-wide state through LoadLocale, a Messages view never
+// touches its factory, so one MessageFactory can serve concurrent requests
+// in different locales.
+type Messages struct {
+	dictionary map[string]string
+	activeTag  language.Tag
+}
+
+// tag implements localeView.
+func (m *Messages) tag() language.Tag {
+	return m.activeTag
+}
+
+// lookup implements localeView.
+func (m *Messages) lookup(messageKey string) (string, bool) {
+	message, ok := m.dictionary[messageKey]
+	return message, ok
+}
+
+// onReload implements localeView. A Messages view is a one-shot snapshot
+// bound to a single request, so it never replays string field
+// initializers — initializeField already resolves them against m.dictionary
+// up front.
+func (m *Messages) onReload(stringInitializer) {}
+
+// Init initializes the message fields of a structure pointer against this
+// view's locale.
+func (m *Messages) Init(structPtr interface{}) interface{} {
+	initializeStruct(m, structPtr)
+
+	return structPtr
+}
+
+// WithContext returns the Messages view for the locale negotiated in ctx
+// (see WithTag, Middleware and UnaryServerInterceptor), falling back to mf's
+// most recently loaded locale if ctx carries none.
+func (mf *MessageFactory) WithContext(ctx context.Context) *Messages {
+	tag, ok := TagFromContext(ctx)
+	if !ok {
+		tag = mf.tag()
+	}
+
+	return &Messages{
+		dictionary: mf.dictionaryFor(tag),
+		activeTag:  tag,
+	}
+}
+
+// negotiateTag matches acceptLanguage against mf's registered locales and
+// returns the registered language.Tag it resolves to.
+//
+// language.MatchStrings can return a decorated variant of the matched tag
+// (e.g. negotiating "de-DE" against a registered "de" yields
+// "de-u-rg-dezzzz", not "de"), which would miss both mf.locales and the
+// dictionary cache on an exact-equality lookup. Resolving through the
+// matcher's returned index instead of its returned tag guarantees the tag
+// used downstream is always one of mf.Locales().
+//
+// It negotiates through mf.matcherFor, whose matcher is built from a
+// consistently-ordered tag slice rather than an unordered map: since
+// language.NewMatcher treats its first argument as the fallback tag,
+// reconstructing the matcher from a freshly map-ranged slice on every call
+// would hand back a random registered locale whenever acceptLanguage
+// doesn't match well.
+func (mf *MessageFactory) negotiateTag(acceptLanguage string) language.Tag {
+	matcher, locales := mf.matcherFor()
+	if len(locales) == 0 {
+		return language.Und
+	}
+
+	_, index := language.MatchStrings(matcher, acceptLanguage)
+
+	return locales[index]
+}
+
+// FromRequest negotiates the best locale for r against mf's registered
+// locales, based on its Accept-Language header.
+func (mf *MessageFactory) FromRequest(r *http.Request) language.Tag {
+	return mf.negotiateTag(r.Header.Get("Accept-Language"))
+}
+
+// Middleware negotiates a locale from each request's Accept-Language header
+// and stashes it in the request context, so that handlers further down the
+// chain can call mf.WithContext(r.Context()).
+func (mf *MessageFactory) Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx := WithTag(r.Context(), mf.FromRequest(r))
+		next.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
+
+// UnaryServerInterceptor negotiates a locale from the "accept-language"
+// incoming gRPC metadata and stashes it in the handler's context, so that it
+// can call mf.WithContext(ctx).
+func (mf *MessageFactory) UnaryServerInterceptor() grpc.UnaryServerInterceptor {
+	return func(
+		ctx context.Context,
+		req interface{},
+		info *grpc.UnaryServerInfo,
+		handler grpc.UnaryHandler) (interface{}, error) {
+
+		var acceptLanguage string
+		if md, ok := metadata.FromIncomingContext(ctx); ok {
+			if values := md.Get("accept-language"); len(values) > 0 {
+				acceptLanguage = values[0]
+			}
+		}
+
+		return handler(WithTag(ctx, mf.negotiateTag(acceptLanguage)), req)
+	}
+}
@@ -3,6 +3,7 @@ package g11n
 import (
 	"fmt"
 	"reflect"
+	"sync"
 
 	g11nLocale "github.com/sgatev/g11n/locale"
 
@@ -29,6 +30,17 @@ type paramFormatter interface {
 	G11nParam() string
 }
 
+// localeParamFormatter represents a type that supports custom formatting,
+// tailored to a specific locale, when it is used as parameter in a call to
+// a g11n message. It takes precedence over paramFormatter when a type
+// implements both.
+type localeParamFormatter interface {
+
+	// G11nParamLocale formats a type in a specific way for tag when passed
+	// to a g11n message.
+	G11nParamLocale(tag language.Tag) interface{}
+}
+
 // resultFormatter represents a type that supports custom formatting
 // when it is returned from a call to a g11n message.
 type resultFormatter interface {
@@ -39,10 +51,37 @@ type resultFormatter interface {
 
 type stringInitializer func()
 
-// formatParam extracts the data from a reflected argument value and returns it.
-func formatParam(value reflect.Value) interface{} {
+// localeView supplies everything initializeField/messageHandler need to
+// resolve a message: the active tag, a translation lookup, and a hook for
+// registering string fields that must be refreshed when the view's
+// translations change.
+//
+// MessageFactory and Messages both implement it, letting the same
+// initialization code serve the factory's legacy global locale and a
+// request-scoped Messages view.
+type localeView interface {
+
+	// tag returns the locale that messages should be rendered in.
+	tag() language.Tag
+
+	// lookup returns the translation for messageKey, if one exists.
+	lookup(messageKey string) (string, bool)
+
+	// onReload registers a string field initializer to be re-run whenever
+	// the view's translations change. Views that never change, such as a
+	// Messages snapshot, may ignore it.
+	onReload(init stringInitializer)
+}
+
+// formatParam extracts the data from a reflected argument value, rendering
+// it for tag if it supports locale-aware formatting.
+func formatParam(tag language.Tag, value reflect.Value) interface{} {
 	valueInterface := value.Interface()
 
+	if localeFormatter, ok := valueInterface.(localeParamFormatter); ok {
+		return localeFormatter.G11nParamLocale(tag)
+	}
+
 	if paramFormatter, ok := valueInterface.(paramFormatter); ok {
 		return paramFormatter.G11nParam()
 	}
@@ -60,31 +99,62 @@ type localeInfo struct {
 // translations to messages.
 type MessageFactory struct {
 	locales            map[language.Tag]localeInfo
+	localeOrder        []language.Tag
 	dictionary         map[string]string
 	stringInitializers []stringInitializer
+	activeTag          language.Tag
+
+	mu           sync.RWMutex
+	dictionaries map[language.Tag]map[string]string
+	matcher      language.Matcher
+	matcherSize  int
 }
 
 // New returns a fresh G11n message factory.
 func New() *MessageFactory {
 	return &MessageFactory{
-		dictionary: map[string]string{},
-		locales:    map[language.Tag]localeInfo{},
+		dictionary:   map[string]string{},
+		locales:      map[language.Tag]localeInfo{},
+		dictionaries: map[language.Tag]map[string]string{},
 	}
 }
 
-// Locales returns the registered locales in a message factory.
+// Locales returns the registered locales in a message factory, in the order
+// they were registered.
 func (mf *MessageFactory) Locales() []language.Tag {
-	locales := make([]language.Tag, 0, len(mf.locales))
+	locales := make([]language.Tag, len(mf.localeOrder))
+	copy(locales, mf.localeOrder)
+
+	return locales
+}
 
-	for locale := range mf.locales {
-		locales = append(locales, locale)
+// matcherFor returns a language.Matcher built from mf's registered locales,
+// along with the ordered tag slice the matcher was built from.
+//
+// language.NewMatcher treats its first argument as the fallback tag, so the
+// slice it's built from must be in a stable order across calls — ranging
+// over mf.locales directly would reorder randomly on every call (Go
+// randomizes map iteration order) and negotiate a different fallback locale
+// from one request to the next. The matcher is cached and only rebuilt when
+// a new locale is registered.
+func (mf *MessageFactory) matcherFor() (language.Matcher, []language.Tag) {
+	mf.mu.Lock()
+	defer mf.mu.Unlock()
+
+	if mf.matcher == nil || mf.matcherSize != len(mf.localeOrder) {
+		mf.matcher = language.NewMatcher(mf.localeOrder)
+		mf.matcherSize = len(mf.localeOrder)
 	}
 
-	return locales
+	return mf.matcher, mf.localeOrder
 }
 
 // SetLocale registers a locale file in the specified format.
 func (mf *MessageFactory) SetLocale(tag language.Tag, format, path string) {
+	if _, ok := mf.locales[tag]; !ok {
+		mf.localeOrder = append(mf.localeOrder, tag)
+	}
+
 	mf.locales[tag] = localeInfo{
 		format: format,
 		path:   path,
@@ -111,36 +181,103 @@ func (mf *MessageFactory) LoadLocale(tag language.Tag) {
 		panic(fmt.Sprintf(unknownFormatMessage, locale.format))
 	}
 
-	mf.dictionary = loader.Load(locale.path)
+	dictionary := loader.Load(locale.path)
+
+	mf.mu.Lock()
+	mf.dictionary = dictionary
+	mf.activeTag = tag
+	mf.dictionaries[tag] = dictionary
+	initializers := append([]stringInitializer(nil), mf.stringInitializers...)
+	mf.mu.Unlock()
 
-	for _, initializer := range mf.stringInitializers {
+	for _, initializer := range initializers {
 		initializer()
 	}
 }
 
+// dictionaryFor returns the parsed locale dictionary for tag, loading and
+// caching it on first use. It is safe for concurrent use by multiple
+// requests.
+func (mf *MessageFactory) dictionaryFor(tag language.Tag) map[string]string {
+	mf.mu.RLock()
+	dictionary, ok := mf.dictionaries[tag]
+	fallback := mf.dictionary
+	mf.mu.RUnlock()
+	if ok {
+		return dictionary
+	}
+
+	locale, ok := mf.locales[tag]
+	if !ok {
+		return fallback
+	}
+
+	loader, ok := g11nLocale.GetLoader(locale.format)
+	if !ok {
+		return fallback
+	}
+
+	dictionary = loader.Load(locale.path)
+
+	mf.mu.Lock()
+	mf.dictionaries[tag] = dictionary
+	mf.mu.Unlock()
+
+	return dictionary
+}
+
+// tag implements localeView. It is safe for concurrent use, guarding the
+// same fields LoadLocale mutates.
+func (mf *MessageFactory) tag() language.Tag {
+	mf.mu.RLock()
+	defer mf.mu.RUnlock()
+
+	return mf.activeTag
+}
+
+// lookup implements localeView. It is safe for concurrent use, guarding the
+// same fields LoadLocale mutates.
+func (mf *MessageFactory) lookup(messageKey string) (string, bool) {
+	mf.mu.RLock()
+	defer mf.mu.RUnlock()
+
+	message, ok := mf.dictionary[messageKey]
+	return message, ok
+}
+
+// onReload implements localeView. It is safe for concurrent use, guarding
+// the same fields LoadLocale mutates.
+func (mf *MessageFactory) onReload(init stringInitializer) {
+	mf.mu.Lock()
+	defer mf.mu.Unlock()
+
+	mf.stringInitializers = append(mf.stringInitializers, init)
+}
+
 // Init initializes the message fields of a structure pointer.
 func (mf *MessageFactory) Init(structPtr interface{}) interface{} {
-	mf.initializeStruct(structPtr)
+	initializeStruct(mf, structPtr)
 
 	return structPtr
 }
 
 // messageHandler creates a handler that formats a message based on provided parameters.
-func (mf *MessageFactory) messageHandler(messagePattern, messageKey string, resultType reflect.Type) func([]reflect.Value) []reflect.Value {
+func messageHandler(view localeView, messagePattern, messageKey string, resultType reflect.Type) func([]reflect.Value) []reflect.Value {
 	return func(args []reflect.Value) []reflect.Value {
 		// Extract localized message.
-		if message, ok := mf.dictionary[messageKey]; ok {
+		if message, ok := view.lookup(messageKey); ok {
 			messagePattern = message
 		}
+		tag := view.tag()
 
 		// Format message parameters.
 		var formattedParams []interface{}
 		for _, arg := range args {
-			formattedParams = append(formattedParams, formatParam(arg))
+			formattedParams = append(formattedParams, formatParam(tag, arg))
 		}
 
 		// Find the result message value.
-		message := fmt.Sprintf(messagePattern, formattedParams...)
+		message := formatMessage(tag, messagePattern, formattedParams)
 		messageValue := reflect.ValueOf(message)
 
 		// Format message result.
@@ -156,7 +293,7 @@ func (mf *MessageFactory) messageHandler(messagePattern, messageKey string, resu
 }
 
 // initializeStruct initializes the message fields of a struct pointer.
-func (mf *MessageFactory) initializeStruct(structPtr interface{}) {
+func initializeStruct(view localeView, structPtr interface{}) {
 	instance := reflect.Indirect(reflect.ValueOf(structPtr))
 	concreteType := instance.Type()
 
@@ -166,15 +303,16 @@ func (mf *MessageFactory) initializeStruct(structPtr interface{}) {
 		instanceField := instance.FieldByName(field.Name)
 
 		if field.Anonymous {
-			mf.initializeEmbeddedStruct(field, instanceField)
+			initializeEmbeddedStruct(view, field, instanceField)
 		} else {
-			mf.initializeField(concreteType, field, instanceField)
+			initializeField(view, concreteType, field, instanceField)
 		}
 	}
 }
 
 // initializeEmbeddedStruct initializes the message fields of an embedded struct.
-func (mf *MessageFactory) initializeEmbeddedStruct(
+func initializeEmbeddedStruct(
+	view localeView,
 	field reflect.StructField,
 	instanceField reflect.Value) {
 
@@ -183,11 +321,12 @@ func (mf *MessageFactory) initializeEmbeddedStruct(
 	instanceField.Set(embeddedStruct)
 
 	// Initialize the messages of the embedded struct.
-	mf.initializeStruct(embeddedStruct.Interface())
+	initializeStruct(view, embeddedStruct.Interface())
 }
 
 // initializeField initializes a message field.
-func (mf *MessageFactory) initializeField(
+func initializeField(
+	view localeView,
 	concreteType reflect.Type,
 	field reflect.StructField,
 	instanceField reflect.Value) {
@@ -200,22 +339,27 @@ func (mf *MessageFactory) initializeField(
 	if field.Type.Kind() == reflect.String {
 		// Initialize string field.
 
-		message := messagePattern
+		resolve := func() string {
+			message := messagePattern
+
+			// Extract localized message, looked up fresh every time since a
+			// Messages view is never reloaded in place.
+			if translated, ok := view.lookup(messageKey); ok {
+				message = translated
+			}
+
+			return message
+		}
+
+		message := resolve()
 
 		// Format message result.
 		if resultFormatter, ok := instanceField.Interface().(resultFormatter); ok {
 			message = resultFormatter.G11nResult(message)
 		}
 
-		mf.stringInitializers = append(mf.stringInitializers, func() {
-			message := messagePattern
-
-			// Extract localized message.
-			if messagePattern, ok := mf.dictionary[messageKey]; ok {
-				message = messagePattern
-			}
-
-			instanceField.SetString(message)
+		view.onReload(func() {
+			instanceField.SetString(resolve())
 		})
 
 		instanceField.SetString(message)
@@ -231,7 +375,7 @@ func (mf *MessageFactory) initializeField(
 
 		// Create proxy function for handling the message.
 		messageProxyFunc := reflect.MakeFunc(
-			field.Type, mf.messageHandler(messagePattern, messageKey, resultType))
+			field.Type, messageHandler(view, messagePattern, messageKey, resultType))
 
 		instanceField.Set(messageProxyFunc)
 	}
@@ -0,0 +1,376 @@
+package g11n
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"golang.org/x/text/feature/plural"
+	"golang.org/x/text/language"
+	"golang.org/x/text/message"
+)
+
+// messageClause represents a single keyword/pattern pair within a plural,
+// selectordinal or select construct, e.g. `one {# item}`.
+type messageClause struct {
+	keyword string
+	pattern string
+}
+
+// messageArg represents a parsed `{name, kind, clauses}` construct found in
+// a message pattern. A kind of "" denotes a plain `{name}` placeholder.
+type messageArg struct {
+	name    string
+	kind    string
+	clauses []messageClause
+}
+
+// messageSegment is either a literal run of text or a parsed messageArg.
+type messageSegment struct {
+	literal string
+	arg     *messageArg
+}
+
+// formatMessage renders messagePattern against args, resolving any
+// CLDR-style plural/selectordinal/select constructs for tag along the way.
+//
+// Patterns that contain no `{...}` construct at all, or whose braces cannot
+// be parsed, are formatted with a message.Printer for tag, so that verbs
+// such as %d and %f render numbers the way tag's locale expects. In a
+// pattern that does contain constructs, the text around them is kept as is.
+func formatMessage(tag language.Tag, messagePattern string, args []interface{}) string {
+	if !strings.Contains(messagePattern, "{") {
+		return message.NewPrinter(tag).Sprintf(messagePattern, args...)
+	}
+
+	segments, ok := parseMessagePattern(messagePattern)
+	if !ok {
+		return message.NewPrinter(tag).Sprintf(messagePattern, args...)
+	}
+
+	var message strings.Builder
+	for _, segment := range segments {
+		if segment.arg == nil {
+			message.WriteString(segment.literal)
+			continue
+		}
+
+		message.WriteString(resolveMessageArg(tag, *segment.arg, args))
+	}
+
+	return message.String()
+}
+
+// resolveMessageArg formats a single parsed construct against args.
+func resolveMessageArg(tag language.Tag, arg messageArg, args []interface{}) string {
+	value, ok := resolveArgValue(arg.name, args)
+	if !ok {
+		return "{" + arg.name + "}"
+	}
+
+	switch arg.kind {
+	case "":
+		return message.NewPrinter(tag).Sprintf("%v", value)
+
+	case "select":
+		clause := resolveKeywordClause(arg, fmt.Sprintf("%v", value))
+		if !strings.Contains(clause, "{") {
+			return clause
+		}
+		return formatMessage(tag, clause, args)
+
+	case "plural", "selectordinal":
+		n, ok := toInt(value)
+		if !ok {
+			return message.NewPrinter(tag).Sprintf("%v", value)
+		}
+
+		keyword := pluralKeyword(tag, n, arg.kind == "selectordinal")
+		clause := strings.ReplaceAll(resolveNumberClause(arg, keyword, n), "#", strconv.Itoa(n))
+		if !strings.Contains(clause, "{") {
+			return clause
+		}
+		return formatMessage(tag, clause, args)
+
+	default:
+		return message.NewPrinter(tag).Sprintf("%v", value)
+	}
+}
+
+// resolveArgValue looks up the value referenced by an arg name. Named
+// arguments are supported when args holds a single map[string]interface{};
+// otherwise name is treated as a positional argument index.
+func resolveArgValue(name string, args []interface{}) (interface{}, bool) {
+	if len(args) == 1 {
+		if named, ok := args[0].(map[string]interface{}); ok {
+			value, ok := named[name]
+			return value, ok
+		}
+	}
+
+	index, err := strconv.Atoi(name)
+	if err != nil || index < 0 || index >= len(args) {
+		return nil, false
+	}
+
+	return args[index], true
+}
+
+// resolveNumberClause picks the plural/selectordinal clause matching n,
+// preferring an exact `=n` clause over the CLDR keyword, and falling back to
+// "other".
+func resolveNumberClause(arg messageArg, keyword string, n int) string {
+	exact := fmt.Sprintf("=%d", n)
+
+	var matched, other string
+	hasMatch := false
+	for _, clause := range arg.clauses {
+		switch clause.keyword {
+		case exact:
+			return clause.pattern
+		case keyword:
+			if !hasMatch {
+				matched, hasMatch = clause.pattern, true
+			}
+		case "other":
+			other = clause.pattern
+		}
+	}
+
+	if hasMatch {
+		return matched
+	}
+
+	return other
+}
+
+// resolveKeywordClause picks the select clause matching keyword exactly,
+// falling back to "other".
+func resolveKeywordClause(arg messageArg, keyword string) string {
+	var other string
+	for _, clause := range arg.clauses {
+		switch clause.keyword {
+		case keyword:
+			return clause.pattern
+		case "other":
+			other = clause.pattern
+		}
+	}
+
+	return other
+}
+
+// pluralKeyword returns the CLDR plural keyword ("zero", "one", "two",
+// "few", "many" or "other") that n maps to in tag. The CLDR plural rules
+// operate on the absolute value of n (a negative count pluralizes the same
+// as its positive counterpart); passing a negative value to MatchPlural
+// directly panics.
+func pluralKeyword(tag language.Tag, n int, ordinal bool) string {
+	rules := plural.Cardinal
+	if ordinal {
+		rules = plural.Ordinal
+	}
+
+	switch rules.MatchPlural(tag, abs(n), 0, 0, 0, 0) {
+	case plural.Zero:
+		return "zero"
+	case plural.One:
+		return "one"
+	case plural.Two:
+		return "two"
+	case plural.Few:
+		return "few"
+	case plural.Many:
+		return "many"
+	default:
+		return "other"
+	}
+}
+
+// abs returns the absolute value of n.
+func abs(n int) int {
+	if n < 0 {
+		return -n
+	}
+
+	return n
+}
+
+// toInt extracts an integer from value if it holds a numeric type.
+func toInt(value interface{}) (int, bool) {
+	switch v := value.(type) {
+	case int:
+		return v, true
+	case int32:
+		return int(v), true
+	case int64:
+		return int(v), true
+	case float32:
+		return int(v), true
+	case float64:
+		return int(v), true
+	default:
+		return 0, false
+	}
+}
+
+// parseMessagePattern splits messagePattern into literal text runs and
+// top-level `{name, kind, clauses}` constructs. It reports false if a `{` in
+// messagePattern has no matching `}` or a construct cannot be parsed; a `}`
+// outside any construct is kept as literal text.
+func parseMessagePattern(messagePattern string) ([]messageSegment, bool) {
+	runes := []rune(messagePattern)
+
+	var segments []messageSegment
+	var literal strings.Builder
+
+	for i := 0; i < len(runes); i++ {
+		if runes[i] != '{' {
+			literal.WriteRune(runes[i])
+			continue
+		}
+
+		end := matchingBrace(runes, i)
+		if end < 0 {
+			return nil, false
+		}
+
+		if literal.Len() > 0 {
+			segments = append(segments, messageSegment{literal: literal.String()})
+			literal.Reset()
+		}
+
+		arg, ok := parseMessageArg(string(runes[i+1 : end]))
+		if !ok {
+			return nil, false
+		}
+		segments = append(segments, messageSegment{arg: &arg})
+
+		i = end
+	}
+
+	if literal.Len() > 0 {
+		segments = append(segments, messageSegment{literal: literal.String()})
+	}
+
+	return segments, true
+}
+
+// parseMessageArg parses the contents of a `{...}` construct, excluding the
+// enclosing braces.
+func parseMessageArg(body string) (messageArg, bool) {
+	parts := splitTopLevel(body, ',')
+	for i := range parts {
+		parts[i] = strings.TrimSpace(parts[i])
+	}
+
+	if len(parts) == 0 || parts[0] == "" {
+		return messageArg{}, false
+	}
+
+	arg := messageArg{name: parts[0]}
+	if len(parts) == 1 {
+		return arg, true
+	}
+
+	arg.kind = parts[1]
+
+	clauses, ok := parseMessageClauses(strings.TrimSpace(strings.Join(parts[2:], ",")))
+	if !ok {
+		return messageArg{}, false
+	}
+	arg.clauses = clauses
+
+	return arg, true
+}
+
+// parseMessageClauses parses a sequence of `keyword {pattern}` clauses, e.g.
+// `one {# item} other {# items}`.
+func parseMessageClauses(text string) ([]messageClause, bool) {
+	runes := []rune(text)
+
+	var clauses []messageClause
+
+	i := 0
+	for i < len(runes) {
+		for i < len(runes) && runes[i] == ' ' {
+			i++
+		}
+		if i >= len(runes) {
+			break
+		}
+
+		keywordStart := i
+		for i < len(runes) && runes[i] != '{' && runes[i] != ' ' {
+			i++
+		}
+		keyword := string(runes[keywordStart:i])
+
+		for i < len(runes) && runes[i] == ' ' {
+			i++
+		}
+		if i >= len(runes) || runes[i] != '{' {
+			return nil, false
+		}
+
+		end := matchingBrace(runes, i)
+		if end < 0 {
+			return nil, false
+		}
+
+		clauses = append(clauses, messageClause{
+			keyword: keyword,
+			pattern: string(runes[i+1 : end]),
+		})
+
+		i = end + 1
+	}
+
+	return clauses, true
+}
+
+// matchingBrace returns the index of the brace matching the '{' found at
+// open, or -1 if the braces in runes are not balanced.
+func matchingBrace(runes []rune, open int) int {
+	depth := 0
+	for i := open; i < len(runes); i++ {
+		switch runes[i] {
+		case '{':
+			depth++
+		case '}':
+			depth--
+			if depth == 0 {
+				return i
+			}
+		}
+	}
+
+	return -1
+}
+
+// splitTopLevel splits s on sep, ignoring occurrences of sep nested inside
+// braces.
+func splitTopLevel(s string, sep rune) []string {
+	var parts []string
+
+	depth := 0
+	var current strings.Builder
+	for _, r := range s {
+		switch {
+		case r == '{':
+			depth++
+			current.WriteRune(r)
+		case r == '}':
+			depth--
+			current.WriteRune(r)
+		case r == sep && depth == 0:
+			parts = append(parts, current.String())
+			current.Reset()
+		default:
+			current.WriteRune(r)
+		}
+	}
+	parts = append(parts, current.String())
+
+	return parts
+}